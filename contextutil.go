@@ -3,6 +3,7 @@ package contextutil
 
 import (
 	"context"
+	"reflect"
 	"sync"
 	"time"
 )
@@ -12,49 +13,182 @@ import (
 // contexts. Done() returns a channel that is closed when any of the given
 // context's Done channels are closed and Err() returns its associated Err().
 // Value() returns the first non-nil value from the given contexts, in the order
-// given.
+// given. context.Cause() reports the cause of whichever child context became
+// Done first, as returned by context.Cause() on that child.
 //
 // Canceling this context releases resources associated with it, so code should
 // call the returned cancel function as soon as the operations running in this
 // Context complete.
 func MultiContext(ctxs ...context.Context) (context.Context, context.CancelFunc) {
+	mc := newMultiContext(ctxs)
+	return mc, func() { mc.finish(context.Canceled, context.Canceled) }
+}
+
+// MultiContextCause behaves like MultiContext, but the returned
+// CancelCauseFunc records an explicit error that is later retrievable via
+// context.Cause. Calling it with a nil error records context.Canceled as the
+// cause, matching context.WithCancelCause.
+func MultiContextCause(ctxs ...context.Context) (context.Context, context.CancelCauseFunc) {
+	mc := newMultiContext(ctxs)
+	return mc, func(cause error) {
+		if cause == nil {
+			cause = context.Canceled
+		}
+		mc.finish(context.Canceled, cause)
+	}
+}
+
+// AfterFunc arranges to call f in its own goroutine after ctx is done
+// (canceled or timed out). If ctx is already done, AfterFunc calls f
+// immediately in its own goroutine. Calling stop prevents f from running if
+// it hasn't started, and reports whether it stopped f from running.
+//
+// When ctx was returned by MultiContext or MultiContextCause, the callback is served directly from its
+// single cancellation path instead of spawning a goroutine to watch
+// ctx.Done(); for any other context, AfterFunc falls back to the stdlib
+// context.AfterFunc.
+func AfterFunc(ctx context.Context, f func()) (stop func() bool) {
+	if mc, ok := ctx.(*multiContext); ok {
+		return mc.afterFunc(f)
+	}
+	return context.AfterFunc(ctx, f)
+}
+
+func newMultiContext(ctxs []context.Context) *multiContext {
+	// causeCtx exists only so that context.Cause(mc) can find a cause:
+	// context.Cause looks up ctx.Value(&cancelCtxKey) for a real *cancelCtx,
+	// a key we have no access to from outside the context package. Delegating
+	// to a genuine context.WithCancelCause lets that lookup succeed without
+	// us needing to know the key.
+	causeCtx, causeCancel := context.WithCancelCause(context.Background())
 	mc := &multiContext{
-		ctxs: ctxs,
-		done: make(chan struct{}),
+		ctxs:        ctxs,
+		done:        make(chan struct{}),
+		causeCtx:    causeCtx,
+		causeCancel: causeCancel,
 	}
-	mc.selectCtxs()
-	return mc, mc.cancel
+	mc.watch()
+	return mc
 }
 
 type multiContext struct {
-	ctxs []context.Context
-	once sync.Once
-	done chan struct{}
-	err  error
+	ctxs        []context.Context
+	once        sync.Once
+	done        chan struct{}
+	causeCtx    context.Context
+	causeCancel context.CancelCauseFunc
+
+	mu              sync.Mutex
+	err             error
+	cause           error
+	watchStop       func() bool
+	afterFuncs      map[int]func()
+	nextAfterFuncID int
 }
 
-func (mc *multiContext) cancel() {
+// finish records err and cause and closes done, the first time it's called.
+func (mc *multiContext) finish(err, cause error) {
 	mc.once.Do(func() {
+		mc.mu.Lock()
+		mc.err = err
+		mc.cause = cause
+		fs := mc.afterFuncs
+		mc.afterFuncs = nil
+		stop := mc.watchStop
+		// done is closed under mu so that afterFunc, which checks done under
+		// mu, cannot register a callback after fs has been taken.
+		mc.causeCancel(cause)
 		close(mc.done)
-		if mc.err == nil {
-			mc.err = context.Canceled
+		mc.mu.Unlock()
+
+		if stop != nil {
+			stop()
+		}
+		for _, f := range fs {
+			go f()
 		}
 	})
 }
 
-func (mc *multiContext) selectCtxs() {
+// afterFunc registers f to run in its own goroutine once mc is done, without
+// spawning a monitoring goroutine per registration: callbacks are kept in a
+// slice-like map guarded by mu and invoked from the single finish() call that
+// closes done.
+func (mc *multiContext) afterFunc(f func()) (stop func() bool) {
+	mc.mu.Lock()
+	select {
+	case <-mc.done:
+		mc.mu.Unlock()
+		go f()
+		return func() bool { return false }
+	default:
+	}
+
+	id := mc.nextAfterFuncID
+	mc.nextAfterFuncID++
+	if mc.afterFuncs == nil {
+		mc.afterFuncs = make(map[int]func())
+	}
+	mc.afterFuncs[id] = f
+	mc.mu.Unlock()
+
+	return func() bool {
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+		_, ok := mc.afterFuncs[id]
+		delete(mc.afterFuncs, id)
+		return ok
+	}
+}
+
+// watch arranges for mc to finish when one of its children does, without
+// spawning one goroutine per child: a child that can never be Done (such as
+// context.Background()) needs no watching at all; a single relevant child is
+// watched via AfterFunc, which itself avoids a dedicated goroutine for
+// cancelCtx-derived contexts; only two or more relevant children fall back to
+// a single goroutine multiplexing over all of their Done channels.
+func (mc *multiContext) watch() {
+	var withDone []context.Context
 	for _, ctx := range mc.ctxs {
-		go func(ctx context.Context) {
-			select {
-			case <-ctx.Done():
-				mc.err = ctx.Err()
-				mc.cancel()
-			case <-mc.done:
-			}
-		}(ctx)
+		if ctx.Done() != nil {
+			withDone = append(withDone, ctx)
+		}
+	}
+	switch len(withDone) {
+	case 0:
+		return
+	case 1:
+		ctx := withDone[0]
+		stop := AfterFunc(ctx, func() {
+			mc.finish(ctx.Err(), context.Cause(ctx))
+		})
+		mc.mu.Lock()
+		mc.watchStop = stop
+		mc.mu.Unlock()
+	default:
+		go mc.selectMany(withDone)
 	}
 }
 
+// selectMany blocks until one of ctxs or mc itself is done, using
+// reflect.Select so a single goroutine can wait on an arbitrary number of
+// Done channels instead of one goroutine per channel.
+func (mc *multiContext) selectMany(ctxs []context.Context) {
+	cases := make([]reflect.SelectCase, len(ctxs)+1)
+	for i, ctx := range ctxs {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	}
+	cases[len(ctxs)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(mc.done)}
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == len(ctxs) {
+		// mc.done was closed directly, e.g. by an explicit cancel.
+		return
+	}
+	ctx := ctxs[chosen]
+	mc.finish(ctx.Err(), context.Cause(ctx))
+}
+
 func (mc *multiContext) Deadline() (deadline time.Time, ok bool) {
 	var found bool
 	min := time.Unix(1<<63-62135596801, 999999999)
@@ -75,10 +209,25 @@ func (mc *multiContext) Done() <-chan struct{} {
 }
 
 func (mc *multiContext) Err() error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 	return mc.err
 }
 
+// Cause returns the cause of the first child context to become Done (as
+// reported by context.Cause), or the error passed to the cancel function
+// returned by MultiContextCause, whichever came first. It returns nil until
+// the context is done.
+func (mc *multiContext) Cause() error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.cause
+}
+
 func (mc *multiContext) Value(key interface{}) interface{} {
+	if v := mc.causeCtx.Value(key); v != nil {
+		return v
+	}
 	for _, ctx := range mc.ctxs {
 		if v := ctx.Value(key); v != nil {
 			return v
@@ -86,3 +235,69 @@ func (mc *multiContext) Value(key interface{}) interface{} {
 	}
 	return nil
 }
+
+// WithoutCancel returns a context that carries parent's values but is never
+// canceled: its Done() is nil, its Err() is always nil, and it has no
+// deadline. This mirrors the Go 1.21 context.WithoutCancel and is a common
+// companion to MultiContext: fan work out onto a context that should outlive
+// the request but still carry request-scoped values such as trace IDs or
+// auth, then use Detach if the work also needs its own cancellation.
+func WithoutCancel(parent context.Context) context.Context {
+	if parent == nil {
+		panic("contextutil: cannot create context from nil parent")
+	}
+	return withoutCancelCtx{parent: parent}
+}
+
+type withoutCancelCtx struct {
+	parent context.Context
+}
+
+func (withoutCancelCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (withoutCancelCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (withoutCancelCtx) Err() error {
+	return nil
+}
+
+func (c withoutCancelCtx) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// Detach returns a context whose values come from parent but whose
+// cancellation and deadline come from lifetime. It is WithoutCancel
+// generalized to an explicit lifetime instead of "never": combine it with
+// MultiContext to bound fanned-out work by, say, a shutdown context while
+// still reading the original request's values.
+func Detach(parent, lifetime context.Context) context.Context {
+	if parent == nil || lifetime == nil {
+		panic("contextutil: cannot create context from nil parent or lifetime")
+	}
+	return detachedCtx{parent: parent, lifetime: lifetime}
+}
+
+type detachedCtx struct {
+	parent   context.Context
+	lifetime context.Context
+}
+
+func (c detachedCtx) Deadline() (deadline time.Time, ok bool) {
+	return c.lifetime.Deadline()
+}
+
+func (c detachedCtx) Done() <-chan struct{} {
+	return c.lifetime.Done()
+}
+
+func (c detachedCtx) Err() error {
+	return c.lifetime.Err()
+}
+
+func (c detachedCtx) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -2,6 +2,7 @@ package contextutil
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -116,6 +117,193 @@ func TestMultiContextNoDeadline(t *testing.T) {
 	}
 }
 
+func TestMultiContextCause(t *testing.T) {
+	errFoo := errors.New("foo")
+	ctx1, cancel1 := context.WithCancelCause(context.Background())
+	defer cancel1(nil)
+	ctx2, cancel2 := context.WithCancelCause(context.Background())
+	defer cancel2(nil)
+
+	mc, cancel := MultiContextCause(ctx1, ctx2)
+	defer cancel(nil)
+
+	cancel1(errFoo)
+	<-mc.Done()
+
+	if mc.Err() != context.Canceled {
+		t.Errorf("expected Err() to be context.Canceled, got %v", mc.Err())
+	}
+	if cause := context.Cause(mc); cause != errFoo {
+		t.Errorf("expected Cause() to be %v, got %v", errFoo, cause)
+	}
+}
+
+func TestMultiContextCauseExplicitCancel(t *testing.T) {
+	errBar := errors.New("bar")
+	mc, cancel := MultiContextCause(context.Background(), context.TODO())
+
+	cancel(errBar)
+	<-mc.Done()
+
+	if mc.Err() != context.Canceled {
+		t.Errorf("expected Err() to be context.Canceled, got %v", mc.Err())
+	}
+	if cause := context.Cause(mc); cause != errBar {
+		t.Errorf("expected Cause() to be %v, got %v", errBar, cause)
+	}
+}
+
+func TestMultiContextCauseDefaultsToCanceled(t *testing.T) {
+	mc, cancel := MultiContextCause(context.Background())
+
+	cancel(nil)
+	<-mc.Done()
+
+	if cause := context.Cause(mc); cause != context.Canceled {
+		t.Errorf("expected Cause() to be context.Canceled, got %v", cause)
+	}
+}
+
+func TestAfterFuncMultiContext(t *testing.T) {
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	mc, cancel := MultiContext(ctx1, context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	stop := AfterFunc(mc, func() { close(done) })
+	defer stop()
+
+	select {
+	case <-done:
+		t.Fatal("AfterFunc ran before the context was done")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel1()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("AfterFunc never ran")
+	}
+}
+
+func TestAfterFuncMultiContextAlreadyDone(t *testing.T) {
+	mc, cancel := MultiContext(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	AfterFunc(mc, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("AfterFunc never ran for an already-done context")
+	}
+}
+
+func TestAfterFuncMultiContextStop(t *testing.T) {
+	mc, cancel := MultiContext(context.Background())
+
+	ran := make(chan struct{})
+	stop := AfterFunc(mc, func() { close(ran) })
+	if stopped := stop(); !stopped {
+		t.Errorf("expected stop() to report true before cancellation")
+	}
+
+	cancel()
+	select {
+	case <-ran:
+		t.Errorf("f ran after being stopped")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if stopped := stop(); stopped {
+		t.Errorf("expected repeated stop() to report false")
+	}
+}
+
+func TestAfterFuncFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	stop := AfterFunc(ctx, func() { close(done) })
+	defer stop()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("AfterFunc never ran for a plain context.Context")
+	}
+}
+
+func TestWithoutCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, one, 1)
+	woc := WithoutCancel(ctx)
+
+	if woc.Done() != nil {
+		t.Errorf("expected Done() to be nil, got %v", woc.Done())
+	}
+	if woc.Err() != nil {
+		t.Errorf("expected Err() to be nil, got %v", woc.Err())
+	}
+	if _, ok := woc.Deadline(); ok {
+		t.Errorf("expected no deadline")
+	}
+	if i, ok := woc.Value(one).(int); !ok || i != 1 {
+		t.Errorf("expected value %v, got %v", 1, i)
+	}
+
+	cancel()
+	if woc.Err() != nil {
+		t.Errorf("expected Err() to remain nil after parent cancellation, got %v", woc.Err())
+	}
+}
+
+func TestDetach(t *testing.T) {
+	parent := context.WithValue(context.Background(), one, 1)
+	lifetime, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := Detach(parent, lifetime)
+	if i, ok := d.Value(one).(int); !ok || i != 1 {
+		t.Errorf("expected value %v, got %v", 1, i)
+	}
+	select {
+	case <-d.Done():
+		t.Fatal("expected Done() to not yet be closed")
+	default:
+	}
+
+	cancel()
+	<-d.Done()
+	if d.Err() != context.Canceled {
+		t.Errorf("expected Err() to be context.Canceled, got %v", d.Err())
+	}
+}
+
+func TestDetachWithMultiContext(t *testing.T) {
+	req := context.WithValue(context.Background(), one, 1)
+	shutdown, shutdownCancel := context.WithCancel(context.Background())
+	defer shutdownCancel()
+
+	mc, cancel := MultiContext(req, shutdown)
+	defer cancel()
+
+	d := Detach(req, mc)
+	if i, ok := d.Value(one).(int); !ok || i != 1 {
+		t.Errorf("expected value %v, got %v", 1, i)
+	}
+
+	shutdownCancel()
+	<-d.Done()
+	if d.Err() != context.Canceled {
+		t.Errorf("expected Err() to be context.Canceled, got %v", d.Err())
+	}
+}
+
 type Key int
 
 var (
@@ -134,3 +322,69 @@ func TestMultiContextValue(t *testing.T) {
 		t.Errorf("expected value %v; got %v", nil, i)
 	}
 }
+
+// TestMultiContextErrRace exercises Err() concurrently with a child
+// cancellation under `go test -race`, guarding against regressions of the
+// data race where err was written and read without synchronization.
+func TestMultiContextErrRace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mc, mcCancel := MultiContext(ctx)
+	defer mcCancel()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mc.Err()
+			}
+		}
+	}()
+
+	cancel()
+	<-mc.Done()
+	close(stop)
+	wg.Wait()
+
+	if mc.Err() != context.Canceled {
+		t.Errorf("expected Err() to be context.Canceled, got %v", mc.Err())
+	}
+}
+
+// BenchmarkMultiContextCancel mirrors the stdlib's
+// BenchmarkCommonParentCancel: it repeatedly builds a MultiContext over many
+// children and cancels one of them, measuring the cost of the redesigned
+// watch path that avoids a goroutine per child.
+func BenchmarkMultiContextCancel(b *testing.B) {
+	const children = 10
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		x := 0
+		for pb.Next() {
+			ctxs := make([]context.Context, children)
+			cancels := make([]context.CancelFunc, children)
+			for i := range ctxs {
+				ctxs[i], cancels[i] = context.WithCancel(context.Background())
+			}
+			mc, cancel := MultiContext(ctxs...)
+			for i := 0; i < 100; i++ {
+				x /= x + 1
+			}
+			cancels[0]()
+			<-mc.Done()
+			cancel()
+			for _, c := range cancels[1:] {
+				c()
+			}
+			for i := 0; i < 100; i++ {
+				x /= x + 1
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package ctxhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoCancelsOnAnyContext(t *testing.T) {
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer srv.Close()
+	defer close(unblock)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := context.Background()
+	shutdown, shutdownCancel := context.WithCancelCause(context.Background())
+	defer shutdownCancel(nil)
+	errShuttingDown := errors.New("shutting down")
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := Do([]context.Context{primary, shutdown}, http.DefaultClient, req)
+		errCh <- err
+	}()
+
+	// Give the request a moment to actually reach the (blocked) handler
+	// before canceling, so we're testing mid-request cancellation rather
+	// than a request that never started.
+	time.Sleep(20 * time.Millisecond)
+	shutdownCancel(errShuttingDown)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected Do to fail with context.Canceled, got %v", err)
+		}
+		if !errors.Is(err, errShuttingDown) {
+			t.Errorf("expected Do's error to wrap the shutdown context's cause %v, got %v", errShuttingDown, err)
+		}
+		if cause := context.Cause(shutdown); cause != errShuttingDown {
+			t.Errorf("expected context.Cause(shutdown) to be %v, got %v", errShuttingDown, cause)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Do did not return promptly after the shutdown context was canceled")
+	}
+}
+
+func TestGetSucceeds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	resp, err := Get([]context.Context{context.Background()}, http.DefaultClient, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestDoDefaultsToRequestContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := Do(nil, http.DefaultClient, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+}